@@ -0,0 +1,28 @@
+package pirsch
+
+// EventStats is the result of Analyzer.Events and holds the number of
+// occurrences and unique visitors for a single event name.
+type EventStats struct {
+	Name     string `db:"name" json:"name"`
+	Count    int    `db:"count" json:"count"`
+	Visitors int    `db:"visitors" json:"visitors"`
+}
+
+// EventBreakdownStats is the result of Analyzer.EventBreakdown and holds the
+// number of occurrences for a single property value of an event.
+type EventBreakdownStats struct {
+	PropertyValue string `db:"property_value" json:"property_value"`
+	Count         int    `db:"count" json:"count"`
+}
+
+// Events returns the number of occurrences and unique visitors per event name for given filter.
+func (analyzer *Analyzer) Events(filter *Filter) ([]EventStats, error) {
+	filter = analyzer.validateFilter(filter)
+	return analyzer.store.Events(filter)
+}
+
+// EventBreakdown returns the number of occurrences per value of given property for given event name and filter.
+func (analyzer *Analyzer) EventBreakdown(name, prop string, filter *Filter) ([]EventBreakdownStats, error) {
+	filter = analyzer.validateFilter(filter)
+	return analyzer.store.EventBreakdown(name, prop, filter)
+}
@@ -0,0 +1,24 @@
+package pirsch
+
+import "time"
+
+// CountryStats is the result of Analyzer.VisitorsByCountry, holding unique
+// visitors for a single country.
+type CountryStats struct {
+	Code     string `db:"code" json:"code"`
+	Visitors int    `db:"visitors" json:"visitors"`
+}
+
+// VisitorsPerCountry is the unique visitor count for a country on a given day.
+type VisitorsPerCountry struct {
+	Day      time.Time `db:"day" json:"day"`
+	Code     string    `db:"code" json:"code"`
+	Visitors int       `db:"visitors" json:"visitors"`
+}
+
+// VisitorsByCountry returns unique visitors grouped by country code for given filter.
+// It returns an empty result without error for hits that were recorded without a GeoDB.
+func (analyzer *Analyzer) VisitorsByCountry(filter *Filter) ([]CountryStats, error) {
+	filter = analyzer.validateFilter(filter)
+	return analyzer.store.VisitorsByCountry(filter)
+}
@@ -0,0 +1,112 @@
+package pirsch
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// BotList holds a set of bot User-Agent substrings and can be refreshed at
+// runtime without losing hits mid-swap: readers always see either the
+// previous or the new snapshot, never a partially populated one.
+type BotList struct {
+	snapshot atomic.Value // []string
+}
+
+// NewBotList returns a BotList seeded with the given bot User-Agent substrings.
+func NewBotList(substrings []string) *BotList {
+	list := &BotList{}
+	list.store(substrings)
+	return list
+}
+
+// Load reads one bot User-Agent substring per line from r and atomically
+// replaces the current snapshot.
+func (list *BotList) Load(r io.Reader) error {
+	var substrings []string
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line != "" && !strings.HasPrefix(line, "#") {
+			substrings = append(substrings, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	list.store(substrings)
+	return nil
+}
+
+// Refresh fetches the bot list from url and atomically replaces the current snapshot.
+// The previous snapshot remains in effect until the fetch succeeds, so a failed
+// refresh never leaves IgnoreHit without a list to consult.
+func (list *BotList) Refresh(url string) error {
+	resp, err := http.Get(url)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("pirsch: bot list refresh failed with status %d", resp.StatusCode)
+	}
+
+	return list.Load(resp.Body)
+}
+
+// RefreshEvery starts a background goroutine that calls Refresh(url) on the
+// given interval until stop is closed.
+func (list *BotList) RefreshEvery(interval time.Duration, url string, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				_ = list.Refresh(url)
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Contains returns true if ua contains any bot substring in the current snapshot.
+func (list *BotList) Contains(ua string) bool {
+	substrings, _ := list.snapshot.Load().([]string)
+
+	for _, botUserAgent := range substrings {
+		if strings.Contains(ua, botUserAgent) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// store lowercases substrings before saving the snapshot, so Contains (which
+// is always called with an already-lowercased User-Agent) behaves the same
+// regardless of whether the list was seeded via NewBotList or loaded via
+// Load/Refresh.
+func (list *BotList) store(substrings []string) {
+	lowered := make([]string, len(substrings))
+
+	for i, s := range substrings {
+		lowered[i] = strings.ToLower(s)
+	}
+
+	list.snapshot.Store(lowered)
+}
@@ -0,0 +1,96 @@
+package pirsch
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestBotListHotReloadDoesNotLoseHits(t *testing.T) {
+	list := NewBotList([]string{"oldbot"})
+	filter := &HitFilter{BotList: list}
+	var wg sync.WaitGroup
+
+	// swap the snapshot concurrently with lookups to make sure Contains
+	// never observes a partially written list
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 100; i++ {
+			if err := list.Load(strings.NewReader("newbot\nanotherbot\n")); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		r := httpRequestWithUserAgent("regular browser ua")
+
+		if filter.IgnoreHit(r) {
+			t.Fatal("regular hit must not be ignored during hot-reload")
+		}
+	}
+
+	wg.Wait()
+
+	if !filter.BotList.Contains("this is newbot crawling") {
+		t.Fatal("BotList must contain the reloaded substring")
+	}
+}
+
+func TestHitFilterIgnoreHitUnknownOrEmptyUserAgent(t *testing.T) {
+	filter := &HitFilter{BotList: NewBotList([]string{"somebot"})}
+
+	if !filter.IgnoreHit(httpRequestWithUserAgent("")) {
+		t.Fatal("empty User-Agent must be ignored")
+	}
+
+	if filter.IgnoreHit(httpRequestWithUserAgent("some unknown but non-empty browser")) {
+		t.Fatal("unknown, non-empty User-Agent must not be ignored")
+	}
+
+	if !filter.IgnoreHit(httpRequestWithUserAgent("Mozilla/5.0 somebot/1.0")) {
+		t.Fatal("known bot User-Agent must be ignored")
+	}
+}
+
+func TestBotListNormalizesCasingRegardlessOfSource(t *testing.T) {
+	seeded := NewBotList([]string{"SomeBot"})
+
+	if !seeded.Contains("mozilla/5.0 somebot/1.0") {
+		t.Fatal("BotList seeded via NewBotList must match case-insensitively")
+	}
+
+	loaded := NewBotList(nil)
+
+	if err := loaded.Load(strings.NewReader("SomeBot\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if !loaded.Contains("mozilla/5.0 somebot/1.0") {
+		t.Fatal("BotList loaded via Load must match case-insensitively")
+	}
+}
+
+func TestUserAgentParserIsBotWiredIntoIgnoreHit(t *testing.T) {
+	filter := &HitFilter{UserAgentParser: MssolaUserAgentParser{}}
+	r := httpRequestWithUserAgent("Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)")
+
+	if !filter.IgnoreHit(r) {
+		t.Fatal("a User-Agent flagged as a bot by the configured parser must be ignored")
+	}
+}
+
+func TestDefaultUserAgentParserIsBotAlwaysFalse(t *testing.T) {
+	if (defaultUserAgentParser{}).IsBot("anything, including crawler/bot text") {
+		t.Fatal("defaultUserAgentParser must defer bot detection entirely to BotList")
+	}
+}
+
+func httpRequestWithUserAgent(ua string) *http.Request {
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("User-Agent", ua)
+	return r
+}
@@ -0,0 +1,121 @@
+package pirsch
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ReferrerSource is a coarse classification of where a referrer comes from.
+type ReferrerSource string
+
+// The set of referrer sources a referrer hostname can be classified as.
+const (
+	ReferrerSourceDirect ReferrerSource = "Direct"
+	ReferrerSourceSearch ReferrerSource = "Search"
+	ReferrerSourceSocial ReferrerSource = "Social"
+	ReferrerSourceEmail  ReferrerSource = "Email"
+	ReferrerSourceOther  ReferrerSource = "Other"
+)
+
+// referrerSourceHostnames maps well-known referrer hostnames to a ReferrerSource.
+// It is intentionally small; unknown hostnames fall back to ReferrerSourceOther.
+var referrerSourceHostnames = map[string]ReferrerSource{
+	"google.com":       ReferrerSourceSearch,
+	"bing.com":         ReferrerSourceSearch,
+	"duckduckgo.com":   ReferrerSourceSearch,
+	"yahoo.com":        ReferrerSourceSearch,
+	"baidu.com":        ReferrerSourceSearch,
+	"facebook.com":     ReferrerSourceSocial,
+	"twitter.com":      ReferrerSourceSocial,
+	"x.com":            ReferrerSourceSocial,
+	"linkedin.com":     ReferrerSourceSocial,
+	"instagram.com":    ReferrerSourceSocial,
+	"reddit.com":       ReferrerSourceSocial,
+	"mail.google.com":  ReferrerSourceEmail,
+	"outlook.com":      ReferrerSourceEmail,
+	"outlook.live.com": ReferrerSourceEmail,
+}
+
+// ClassifyReferrerSource returns the ReferrerSource for given referrer hostname.
+// An empty hostname is classified as ReferrerSourceDirect, a known hostname (ignoring
+// subdomains) as its mapped source, and anything else as ReferrerSourceOther.
+func ClassifyReferrerSource(hostname string) ReferrerSource {
+	if hostname == "" {
+		return ReferrerSourceDirect
+	}
+
+	hostname = strings.ToLower(hostname)
+
+	if source, ok := referrerSourceHostnames[hostname]; ok {
+		return source
+	}
+
+	if source, ok := referrerSourceHostnames[stripSubdomain(hostname)]; ok {
+		return source
+	}
+
+	return ReferrerSourceOther
+}
+
+// referrerHostname extracts the bare hostname from a stored referrer, which is
+// a full URL (as produced by getReferrer in hit.go), not a hostname by itself.
+// It returns an empty string for direct traffic or an unparsable referrer.
+func referrerHostname(referrer string) string {
+	if referrer == "" {
+		return ""
+	}
+
+	u, err := url.Parse(referrer)
+
+	if err != nil {
+		return ""
+	}
+
+	return u.Hostname()
+}
+
+// CampaignStats is the result of Analyzer.CampaignSources, Analyzer.CampaignMediums,
+// and Analyzer.Campaigns, holding unique visitors for a single UTM value.
+type CampaignStats struct {
+	Name     string `db:"name" json:"name"`
+	Visitors int    `db:"visitors" json:"visitors"`
+}
+
+// ReferrerSourceStats is the result of Analyzer.ReferrerSources, holding unique
+// visitors for a single ReferrerSource.
+type ReferrerSourceStats struct {
+	Source   ReferrerSource `db:"source" json:"source"`
+	Visitors int            `db:"visitors" json:"visitors"`
+}
+
+// VisitorsPerCampaign is the unique visitor count for a utm_campaign value on a given day.
+type VisitorsPerCampaign struct {
+	Day      time.Time `db:"day" json:"day"`
+	Campaign string    `db:"campaign" json:"campaign"`
+	Visitors int       `db:"visitors" json:"visitors"`
+}
+
+// CampaignSources returns unique visitors grouped by utm_source for given filter.
+func (analyzer *Analyzer) CampaignSources(filter *Filter) ([]CampaignStats, error) {
+	filter = analyzer.validateFilter(filter)
+	return analyzer.store.CampaignSources(filter)
+}
+
+// CampaignMediums returns unique visitors grouped by utm_medium for given filter.
+func (analyzer *Analyzer) CampaignMediums(filter *Filter) ([]CampaignStats, error) {
+	filter = analyzer.validateFilter(filter)
+	return analyzer.store.CampaignMediums(filter)
+}
+
+// Campaigns returns unique visitors grouped by utm_campaign for given filter.
+func (analyzer *Analyzer) Campaigns(filter *Filter) ([]CampaignStats, error) {
+	filter = analyzer.validateFilter(filter)
+	return analyzer.store.Campaigns(filter)
+}
+
+// ReferrerSources returns unique visitors grouped by ReferrerSource for given filter.
+func (analyzer *Analyzer) ReferrerSources(filter *Filter) ([]ReferrerSourceStats, error) {
+	filter = analyzer.validateFilter(filter)
+	return analyzer.store.ReferrerSources(filter)
+}
@@ -0,0 +1,123 @@
+package pirsch
+
+import (
+	"database/sql"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClassifyReferrerSource(t *testing.T) {
+	input := []struct {
+		referrer string
+		source   ReferrerSource
+	}{
+		{"", ReferrerSourceDirect},
+		{"https://www.google.com/search?q=pirsch", ReferrerSourceSearch},
+		{"https://bing.com/", ReferrerSourceSearch},
+		{"https://m.facebook.com/", ReferrerSourceSocial},
+		{"https://mail.google.com/mail/u/0", ReferrerSourceEmail},
+		{"https://example.com/blog", ReferrerSourceOther},
+	}
+
+	for _, in := range input {
+		if source := ClassifyReferrerSource(referrerHostname(in.referrer)); source != in.source {
+			t.Fatalf("expected %v for referrer %q, but was: %v", in.source, in.referrer, source)
+		}
+	}
+}
+
+func TestGetCampaignParams(t *testing.T) {
+	long := strings.Repeat("a", 300)
+	u, err := url.Parse("/?utm_source=" + long + "&utm_medium=email&gclid=abc123")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &http.Request{URL: u}
+	campaign := getCampaignParams(r)
+
+	if len(campaign.source) != 200 {
+		t.Fatalf("utm_source must be shortened to 200 characters, but was: %v", len(campaign.source))
+	}
+
+	if campaign.medium != "email" {
+		t.Fatalf("utm_medium not as expected: %v", campaign.medium)
+	}
+
+	if campaign.gclid != "abc123" {
+		t.Fatalf("gclid not as expected: %v", campaign.gclid)
+	}
+}
+
+// TestAnalyzerReferrerSourcesDedup makes sure a visitor who shows up under two
+// referrers classified under the same source is only counted once for that source.
+func TestAnalyzerReferrerSourcesDedup(t *testing.T) {
+	store := NewPostgresStore(db)
+	cleanupDB(t)
+	createHitWithReferrer(t, store, "fp1", "https://www.google.com/search", pastDay(0))
+	createHitWithReferrer(t, store, "fp1", "https://bing.com/", pastDay(0))
+	createHitWithReferrer(t, store, "fp2", "https://www.google.com/search", pastDay(0))
+	analyzer := NewAnalyzer(store)
+	stats, err := analyzer.ReferrerSources(&Filter{pastDay(0), pastDay(0)})
+
+	if err != nil {
+		t.Fatalf("ReferrerSources must be returned, but was: %v", err)
+	}
+
+	for _, stat := range stats {
+		if stat.Source == ReferrerSourceSearch && stat.Visitors != 2 {
+			t.Fatalf("Search visitors must be deduped to 2, but was: %v", stat.Visitors)
+		}
+	}
+}
+
+func createHitWithReferrer(t *testing.T, store Store, fingerprint, referrer string, day time.Time) {
+	hit := Hit{
+		Fingerprint: fingerprint,
+		Referrer:    sql.NullString{String: referrer, Valid: referrer != ""},
+		Time:        day,
+	}
+
+	if err := store.SaveHit(&hit); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestStoreRollupVisitorsPerCampaign proves the rollup job actually persists
+// rows into visitors_per_campaign, rather than just computing them.
+func TestStoreRollupVisitorsPerCampaign(t *testing.T) {
+	store := NewPostgresStore(db)
+	cleanupDB(t)
+	createHitWithCampaign(t, store, "fp1", "launch", pastDay(0))
+	createHitWithCampaign(t, store, "fp2", "launch", pastDay(0))
+
+	if err := store.RollupVisitorsPerCampaign(pastDay(0)); err != nil {
+		t.Fatalf("rollup must not return an error, but was: %v", err)
+	}
+
+	var visitors int
+
+	if err := store.db.Get(&visitors, `SELECT visitors FROM visitors_per_campaign WHERE campaign = $1`, "launch"); err != nil {
+		t.Fatalf("rolled up row must be persisted, but was: %v", err)
+	}
+
+	if visitors != 2 {
+		t.Fatalf("visitors not as expected: %v", visitors)
+	}
+}
+
+func createHitWithCampaign(t *testing.T, store Store, fingerprint, campaign string, day time.Time) {
+	hit := Hit{
+		Fingerprint: fingerprint,
+		UTMCampaign: sql.NullString{String: campaign, Valid: true},
+		Time:        day,
+	}
+
+	if err := store.SaveHit(&hit); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,114 @@
+package pirsch
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Properties is a set of arbitrary string key/value pairs attached to an Event.
+// It is persisted as JSONB so events can carry whatever metadata the caller wants
+// without requiring a schema migration for every new property.
+type Properties map[string]string
+
+// Value implements the driver.Valuer interface.
+func (props Properties) Value() (driver.Value, error) {
+	if len(props) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(props)
+}
+
+// Scan implements the sql.Scanner interface.
+func (props *Properties) Scan(value interface{}) error {
+	if value == nil {
+		*props = nil
+		return nil
+	}
+
+	data, ok := value.([]byte)
+
+	if !ok {
+		return errors.New("pirsch: Properties.Scan: unsupported type")
+	}
+
+	return json.Unmarshal(data, props)
+}
+
+// Event represents a single custom event, such as a signup or a download,
+// and is stored alongside Hit so both share the same visitor identity.
+type Event struct {
+	BaseEntity
+
+	Name        string         `db:"name" json:"name"`
+	Fingerprint string         `db:"fingerprint" json:"fingerprint"`
+	Session     sql.NullTime   `db:"session" json:"session"`
+	Path        sql.NullString `db:"path" json:"path,omitempty"`
+	Props       Properties     `db:"props" json:"props,omitempty"`
+	Time        time.Time      `db:"time" json:"time"`
+}
+
+// String implements the Stringer interface.
+func (event Event) String() string {
+	out, _ := json.Marshal(event)
+	return string(out)
+}
+
+// EventOptions is used to manipulate the data saved on an event.
+// It mirrors HitOptions so events can be correlated with the hit that triggered them.
+type EventOptions struct {
+	// TenantID is optionally saved with an event to split the data between multiple tenants.
+	TenantID sql.NullInt64
+
+	// Path can be specified to manually overwrite the path stored for the request.
+	Path string
+
+	// Session is the timestamp this fingerprint was first seen to identify the session.
+	// Pass a zero time.Time to disable session tracking.
+	Session time.Time
+}
+
+// EventFromRequest returns a new Event for given request, salt, name, props and EventOptions.
+// The salt must be the same one passed to HitFromRequest so events share the visitor's fingerprint.
+func EventFromRequest(r *http.Request, salt, name string, props map[string]string, options *EventOptions) Event {
+	now := time.Now().UTC() // capture first to get as close as possible
+
+	if options == nil {
+		options = &EventOptions{}
+	}
+
+	path := options.Path
+
+	if path == "" {
+		path = r.URL.Path
+	}
+
+	path = shortenString(path, 2000)
+	name = shortenString(name, 200)
+
+	return Event{
+		BaseEntity:  BaseEntity{TenantID: options.TenantID},
+		Name:        name,
+		Fingerprint: Fingerprint(r, salt),
+		Session:     sql.NullTime{Time: options.Session, Valid: !options.Session.IsZero()},
+		Path:        sql.NullString{String: path, Valid: path != ""},
+		Props:       props,
+		Time:        now,
+	}
+}
+
+// TrackEvent saves a custom event (such as "signup", "download", or "video_play") for given request.
+// It reuses IgnoreHit so events are filtered the same way hits are, and the tracker's
+// store and salt so events share the same visitor identity as hits tracked through Tracker.Hit.
+func (tracker *Tracker) TrackEvent(r *http.Request, name string, props map[string]string, options *EventOptions) error {
+	if IgnoreHit(r) {
+		return nil
+	}
+
+	event := EventFromRequest(r, tracker.salt, name, props, options)
+	return tracker.store.SaveEvent(&event)
+}
@@ -0,0 +1,127 @@
+package pirsch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventFromRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/foo?bar=baz", nil)
+	event := EventFromRequest(r, "salt", "signup", map[string]string{"plan": "pro"}, nil)
+
+	if event.Name != "signup" {
+		t.Fatalf("Name not as expected: %v", event.Name)
+	}
+
+	if !event.Path.Valid || event.Path.String != "/foo" {
+		t.Fatalf("Path not as expected: %v", event.Path)
+	}
+
+	if event.Props["plan"] != "pro" {
+		t.Fatalf("Props not as expected: %v", event.Props)
+	}
+
+	if event.Fingerprint == "" {
+		t.Fatal("Fingerprint must be set")
+	}
+}
+
+func TestEventFromRequestPathOverride(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+	event := EventFromRequest(r, "salt", "download", nil, &EventOptions{Path: "/custom"})
+
+	if event.Path.String != "/custom" {
+		t.Fatalf("Path override not applied: %v", event.Path)
+	}
+}
+
+func TestPropertiesValueScan(t *testing.T) {
+	props := Properties{"plan": "pro", "source": "landing"}
+	value, err := props.Value()
+
+	if err != nil {
+		t.Fatalf("Value must not return an error, but was: %v", err)
+	}
+
+	var scanned Properties
+
+	if err := scanned.Scan(value); err != nil {
+		t.Fatalf("Scan must not return an error, but was: %v", err)
+	}
+
+	if scanned["plan"] != "pro" || scanned["source"] != "landing" {
+		t.Fatalf("Scanned properties not as expected: %v", scanned)
+	}
+}
+
+func TestPropertiesValueEmpty(t *testing.T) {
+	var props Properties
+	value, err := props.Value()
+
+	if err != nil || value != nil {
+		t.Fatalf("Value for empty Properties must be nil, but was: %v, %v", value, err)
+	}
+}
+
+func TestStoreSaveEvents(t *testing.T) {
+	store := NewPostgresStore(db)
+	cleanupDB(t)
+	events := []Event{
+		{Name: "signup", Fingerprint: "fp1", Time: time.Now()},
+		{Name: "download", Fingerprint: "fp2", Time: time.Now()},
+	}
+
+	if err := store.SaveEvents(events); err != nil {
+		t.Fatalf("events must be saved, but was: %v", err)
+	}
+
+	var count int
+
+	if err := store.db.Get(&count, `SELECT COUNT(1) FROM event`); err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 2 {
+		t.Fatalf("expected 2 events to be saved, but was: %v", count)
+	}
+}
+
+// TestStoreSaveEventsRollsBackOnFailure makes sure a failure partway through a
+// batch leaves no partially committed events, since event name is VARCHAR(200).
+func TestStoreSaveEventsRollsBackOnFailure(t *testing.T) {
+	store := NewPostgresStore(db)
+	cleanupDB(t)
+	events := []Event{
+		{Name: "signup", Fingerprint: "fp1", Time: time.Now()},
+		{Name: strings.Repeat("x", 300), Fingerprint: "fp2", Time: time.Now()},
+	}
+
+	if err := store.SaveEvents(events); err == nil {
+		t.Fatal("expected an error for a batch containing an invalid event")
+	}
+
+	var count int
+
+	if err := store.db.Get(&count, `SELECT COUNT(1) FROM event`); err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 0 {
+		t.Fatalf("expected no events to be committed after a failed batch, but was: %v", count)
+	}
+}
+
+func TestTrackerTrackEvent(t *testing.T) {
+	store := NewPostgresStore(db)
+	cleanupDB(t)
+	tracker := NewTracker(store, "salt", nil)
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("User-Agent", "test-ua")
+
+	if err := tracker.TrackEvent(r, "signup", map[string]string{"plan": "pro"}, nil); err != nil {
+		t.Fatalf("event must be tracked, but was: %v", err)
+	}
+}
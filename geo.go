@@ -0,0 +1,117 @@
+package pirsch
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// ipHeaders is the list of headers checked (in order) to find the client's IP address,
+// falling back to the request's RemoteAddr, similar to how the referrer is pulled from
+// either a header or a query parameter.
+var ipHeaders = []string{
+	"X-Forwarded-For",
+	"X-Real-IP",
+}
+
+// GeoDB looks up the country, region, and city for an IP address.
+// Implementations may be backed by a local database or a remote service.
+// Geolocation is entirely optional: HitFromRequest leaves the geo columns NULL
+// when HitOptions.GeoDB is nil.
+type GeoDB interface {
+	// Lookup returns the country code, region, and city for given IP address.
+	Lookup(ip net.IP) (country, region, city string, err error)
+}
+
+// MaxMindGeoDB is a GeoDB backed by a MaxMind GeoLite2/GeoIP2 City mmdb file.
+type MaxMindGeoDB struct {
+	reader *geoip2.Reader
+}
+
+// NewMaxMindGeoDB opens the mmdb file at path and returns a MaxMindGeoDB reading from it.
+// The returned MaxMindGeoDB must be closed once it's no longer needed.
+func NewMaxMindGeoDB(path string) (*MaxMindGeoDB, error) {
+	reader, err := geoip2.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &MaxMindGeoDB{reader: reader}, nil
+}
+
+// Lookup implements the GeoDB interface.
+func (db *MaxMindGeoDB) Lookup(ip net.IP) (string, string, string, error) {
+	record, err := db.reader.City(ip)
+
+	if err != nil {
+		return "", "", "", err
+	}
+
+	country := record.Country.IsoCode
+	city := record.City.Names["en"]
+	region := ""
+
+	if len(record.Subdivisions) > 0 {
+		region = record.Subdivisions[0].IsoCode
+	}
+
+	return country, region, city, nil
+}
+
+// Close closes the underlying mmdb file.
+func (db *MaxMindGeoDB) Close() error {
+	return db.reader.Close()
+}
+
+// lookupGeo returns the country, region, and city for the client IP of r using geoDB.
+// It returns empty strings without error if geoDB is nil, the IP cannot be determined,
+// or the lookup itself fails, so a missing or broken GeoDB never breaks hit tracking.
+func lookupGeo(geoDB GeoDB, r *http.Request) (country, region, city string) {
+	if geoDB == nil {
+		return "", "", ""
+	}
+
+	ip := clientIP(r)
+
+	if ip == nil {
+		return "", "", ""
+	}
+
+	country, region, city, err := geoDB.Lookup(ip)
+
+	if err != nil {
+		return "", "", ""
+	}
+
+	return country, region, city
+}
+
+// clientIP extracts the client's IP address from r, checking ipHeaders in order
+// before falling back to RemoteAddr.
+func clientIP(r *http.Request) net.IP {
+	for _, header := range ipHeaders {
+		value := r.Header.Get(header)
+
+		if value == "" {
+			continue
+		}
+
+		// X-Forwarded-For may contain a comma-separated list; the client is the first entry.
+		parts := strings.Split(value, ",")
+
+		if ip := net.ParseIP(strings.TrimSpace(parts[0])); ip != nil {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return net.ParseIP(host)
+}
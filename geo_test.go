@@ -0,0 +1,124 @@
+package pirsch
+
+import (
+	"database/sql"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeGeoDB struct {
+	country, region, city string
+	err                   error
+}
+
+func (db fakeGeoDB) Lookup(net.IP) (string, string, string, error) {
+	return db.country, db.region, db.city, db.err
+}
+
+func TestClientIP(t *testing.T) {
+	input := []struct {
+		name       string
+		header     string
+		value      string
+		remoteAddr string
+		expected   string
+	}{
+		{"X-Forwarded-For", "X-Forwarded-For", "1.2.3.4, 5.6.7.8", "", "1.2.3.4"},
+		{"X-Real-IP", "X-Real-IP", "9.8.7.6", "", "9.8.7.6"},
+		{"RemoteAddr fallback", "", "", "10.0.0.1:1234", "10.0.0.1"},
+		{"no IP available", "", "", "not-an-ip", ""},
+	}
+
+	for _, in := range input {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = in.remoteAddr
+
+		if in.header != "" {
+			r.Header.Set(in.header, in.value)
+		}
+
+		ip := clientIP(r)
+
+		if in.expected == "" {
+			if ip != nil {
+				t.Fatalf("%s: expected no IP, but was: %v", in.name, ip)
+			}
+
+			continue
+		}
+
+		if ip == nil || ip.String() != in.expected {
+			t.Fatalf("%s: expected %v, but was: %v", in.name, in.expected, ip)
+		}
+	}
+}
+
+func TestLookupGeoNilGeoDB(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "1.2.3.4:1234"
+	country, region, city := lookupGeo(nil, r)
+
+	if country != "" || region != "" || city != "" {
+		t.Fatalf("expected empty geo data for nil GeoDB, but was: %v, %v, %v", country, region, city)
+	}
+}
+
+func TestLookupGeo(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "1.2.3.4:1234"
+	db := fakeGeoDB{country: "US", region: "CA", city: "San Francisco"}
+	country, region, city := lookupGeo(db, r)
+
+	if country != "US" || region != "CA" || city != "San Francisco" {
+		t.Fatalf("geo data not as expected: %v, %v, %v", country, region, city)
+	}
+}
+
+func TestLookupGeoLookupError(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "1.2.3.4:1234"
+	db := fakeGeoDB{err: net.InvalidAddrError("boom")}
+	country, region, city := lookupGeo(db, r)
+
+	if country != "" || region != "" || city != "" {
+		t.Fatalf("expected empty geo data on lookup error, but was: %v, %v, %v", country, region, city)
+	}
+}
+
+// TestStoreRollupVisitorsPerCountry proves the rollup job actually persists
+// rows into visitors_per_country, rather than just computing them.
+func TestStoreRollupVisitorsPerCountry(t *testing.T) {
+	store := NewPostgresStore(db)
+	cleanupDB(t)
+	createHitWithCountry(t, store, "fp1", "US", pastDay(0))
+	createHitWithCountry(t, store, "fp2", "US", pastDay(0))
+
+	if err := store.RollupVisitorsPerCountry(pastDay(0)); err != nil {
+		t.Fatalf("rollup must not return an error, but was: %v", err)
+	}
+
+	var visitors int
+
+	if err := store.db.Get(&visitors, `SELECT visitors FROM visitors_per_country WHERE code = $1`, "US"); err != nil {
+		t.Fatalf("rolled up row must be persisted, but was: %v", err)
+	}
+
+	if visitors != 2 {
+		t.Fatalf("visitors not as expected: %v", visitors)
+	}
+}
+
+func createHitWithCountry(t *testing.T, store Store, fingerprint, code string, day time.Time) {
+	hit := Hit{
+		Fingerprint: fingerprint,
+		CountryCode: sql.NullString{String: code, Valid: true},
+		Time:        day,
+	}
+
+	if err := store.SaveHit(&hit); err != nil {
+		t.Fatal(err)
+	}
+}
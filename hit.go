@@ -32,6 +32,16 @@ type Hit struct {
 	BrowserVersion sql.NullString `db:"browser_version" json:"browser_version,omitempty"`
 	Desktop        bool           `db:"desktop" json:"desktop"`
 	Mobile         bool           `db:"mobile" json:"mobile"`
+	UTMSource      sql.NullString `db:"utm_source" json:"utm_source,omitempty"`
+	UTMMedium      sql.NullString `db:"utm_medium" json:"utm_medium,omitempty"`
+	UTMCampaign    sql.NullString `db:"utm_campaign" json:"utm_campaign,omitempty"`
+	UTMContent     sql.NullString `db:"utm_content" json:"utm_content,omitempty"`
+	UTMTerm        sql.NullString `db:"utm_term" json:"utm_term,omitempty"`
+	Gclid          sql.NullString `db:"gclid" json:"gclid,omitempty"`
+	Fbclid         sql.NullString `db:"fbclid" json:"fbclid,omitempty"`
+	CountryCode    sql.NullString `db:"country_code" json:"country_code,omitempty"`
+	Region         sql.NullString `db:"region" json:"region,omitempty"`
+	City           sql.NullString `db:"city" json:"city,omitempty"`
 	Time           time.Time      `db:"time" json:"time"`
 }
 
@@ -64,6 +74,15 @@ type HitOptions struct {
 	// Session is the timestamp this fingerprint was first seen to identify the session.
 	// Pass a zero time.Time to disable session tracking.
 	Session time.Time
+
+	// GeoDB is used to look up the country, region, and city for a hit's IP address.
+	// The geo columns are left NULL and no lookup is performed if GeoDB is nil,
+	// so geolocation stays entirely optional.
+	GeoDB GeoDB
+
+	// UserAgentParser is used to parse the User-Agent header into OS/Browser information.
+	// The built-in parser (ParseUserAgent) is used if this is nil.
+	UserAgentParser UserAgentParser
 }
 
 // HitFromRequest returns a new Hit for given request, salt and HitOptions.
@@ -96,7 +115,13 @@ func HitFromRequest(r *http.Request, salt string, options *HitOptions) Hit {
 	path := shortenString(options.Path, 2000)
 	requestURL = shortenString(requestURL, 2000)
 	ua := r.UserAgent()
-	uaInfo := ParseUserAgent(ua)
+	uaParser := options.UserAgentParser
+
+	if uaParser == nil {
+		uaParser = defaultUserAgentParser{}
+	}
+
+	uaInfo := uaParser.Parse(ua)
 	uaInfo.OS = shortenString(uaInfo.OS, 20)
 	uaInfo.OSVersion = shortenString(uaInfo.OSVersion, 20)
 	uaInfo.Browser = shortenString(uaInfo.Browser, 20)
@@ -104,6 +129,8 @@ func HitFromRequest(r *http.Request, salt string, options *HitOptions) Hit {
 	ua = shortenString(ua, 200)
 	lang := shortenString(getLanguage(r), 10)
 	referrer := shortenString(getReferrer(r, options.ReferrerDomainBlacklist, options.ReferrerDomainBlacklistIncludesSubdomains), 200)
+	campaign := getCampaignParams(r)
+	country, region, city := lookupGeo(options.GeoDB, r)
 
 	return Hit{
 		BaseEntity:     BaseEntity{TenantID: options.TenantID},
@@ -120,13 +147,51 @@ func HitFromRequest(r *http.Request, salt string, options *HitOptions) Hit {
 		BrowserVersion: sql.NullString{String: uaInfo.BrowserVersion, Valid: uaInfo.BrowserVersion != ""},
 		Desktop:        uaInfo.IsDesktop(),
 		Mobile:         uaInfo.IsMobile(),
+		UTMSource:      sql.NullString{String: campaign.source, Valid: campaign.source != ""},
+		UTMMedium:      sql.NullString{String: campaign.medium, Valid: campaign.medium != ""},
+		UTMCampaign:    sql.NullString{String: campaign.campaign, Valid: campaign.campaign != ""},
+		UTMContent:     sql.NullString{String: campaign.content, Valid: campaign.content != ""},
+		UTMTerm:        sql.NullString{String: campaign.term, Valid: campaign.term != ""},
+		Gclid:          sql.NullString{String: campaign.gclid, Valid: campaign.gclid != ""},
+		Fbclid:         sql.NullString{String: campaign.fbclid, Valid: campaign.fbclid != ""},
+		CountryCode:    sql.NullString{String: country, Valid: country != ""},
+		Region:         sql.NullString{String: region, Valid: region != ""},
+		City:           sql.NullString{String: city, Valid: city != ""},
 		Time:           now,
 	}
 }
 
 // IgnoreHit returns true, if a hit should be ignored for given request, or false otherwise.
 // The easiest way to track visitors is to use the Tracker.
+//
+// IgnoreHit delegates to defaultHitFilter, which consults a BotList seeded
+// from userAgentBlacklist. Use HitFilter directly to swap in a BotList that
+// refreshes itself from an external source.
 func IgnoreHit(r *http.Request) bool {
+	return defaultHitFilter.IgnoreHit(r)
+}
+
+// defaultHitFilter preserves the pre-HitFilter behavior of IgnoreHit for callers
+// that don't need a custom BotList.
+var defaultHitFilter = &HitFilter{BotList: NewBotList(userAgentBlacklist)}
+
+// HitFilter decides whether a hit should be ignored. It consults a BotList
+// snapshot, so the bot list can be refreshed at runtime without losing hits
+// mid-swap, and a UserAgentParser so a pluggable parser's own bot detection
+// (e.g. MssolaUserAgentParser) also affects filtering.
+type HitFilter struct {
+	// BotList is the set of bot User-Agent substrings to filter on.
+	// A nil BotList rejects no User-Agent based on keywords.
+	BotList *BotList
+
+	// UserAgentParser is consulted via its IsBot method in addition to BotList.
+	// The built-in parser (ParseUserAgent) is used if this is nil, which never
+	// flags a User-Agent as a bot on its own.
+	UserAgentParser UserAgentParser
+}
+
+// IgnoreHit returns true, if a hit should be ignored for given request, or false otherwise.
+func (filter *HitFilter) IgnoreHit(r *http.Request) bool {
 	// empty User-Agents are usually bots
 	userAgent := strings.TrimSpace(strings.ToLower(r.Header.Get("User-Agent")))
 
@@ -147,10 +212,19 @@ func IgnoreHit(r *http.Request) bool {
 	}
 
 	// filter for bot keywords
-	for _, botUserAgent := range userAgentBlacklist {
-		if strings.Contains(userAgent, botUserAgent) {
-			return true
-		}
+	if filter.BotList != nil && filter.BotList.Contains(userAgent) {
+		return true
+	}
+
+	// consult the configured parser's own bot detection, if any
+	uaParser := filter.UserAgentParser
+
+	if uaParser == nil {
+		uaParser = defaultUserAgentParser{}
+	}
+
+	if uaParser.IsBot(userAgent) {
+		return true
 	}
 
 	return false
@@ -213,6 +287,30 @@ func getReferrerFromHeaderOrQuery(r *http.Request) string {
 	return referrer
 }
 
+// campaignParams holds the UTM and click-ID query parameters extracted from a request.
+type campaignParams struct {
+	source   string
+	medium   string
+	campaign string
+	content  string
+	term     string
+	gclid    string
+	fbclid   string
+}
+
+func getCampaignParams(r *http.Request) campaignParams {
+	query := r.URL.Query()
+	return campaignParams{
+		source:   shortenString(query.Get("utm_source"), 200),
+		medium:   shortenString(query.Get("utm_medium"), 200),
+		campaign: shortenString(query.Get("utm_campaign"), 200),
+		content:  shortenString(query.Get("utm_content"), 200),
+		term:     shortenString(query.Get("utm_term"), 200),
+		gclid:    shortenString(query.Get("gclid"), 200),
+		fbclid:   shortenString(query.Get("fbclid"), 200),
+	}
+}
+
 func stripSubdomain(hostname string) string {
 	if hostname == "" {
 		return ""
@@ -0,0 +1,68 @@
+package pirsch
+
+import "time"
+
+// defaultSessionTimeout caps how long a session may run so an idle tab left
+// open doesn't inflate AverageSessionDuration.
+const defaultSessionTimeout = 30 * time.Minute
+
+// SessionStatsPerDay is a daily rollup of session-based metrics, written by a
+// background job on Store so dashboards don't have to scan raw hits.
+type SessionStatsPerDay struct {
+	Day                time.Time `db:"day" json:"day"`
+	Sessions           int       `db:"sessions" json:"sessions"`
+	Bounces            int       `db:"bounces" json:"bounces"`
+	AvgDurationSeconds int       `db:"avg_duration_seconds" json:"avg_duration_seconds"`
+	AvgPageviews       float64   `db:"avg_pageviews" json:"avg_pageviews"`
+}
+
+// SessionFilter extends Filter with a minimum session length, used by
+// BounceRate, AverageSessionDuration, and PagesPerSession to exclude
+// sessions shorter than MinSessionDuration.
+type SessionFilter struct {
+	*Filter
+
+	// MinSessionDuration excludes sessions shorter than this duration when set.
+	MinSessionDuration time.Duration
+
+	// SessionTimeout caps the duration of a single session; idle time beyond
+	// this is not counted. Defaults to defaultSessionTimeout when zero.
+	SessionTimeout time.Duration
+}
+
+// BounceRate returns the share of sessions (fingerprint, session) consisting
+// of exactly one hit for given filter.
+func (analyzer *Analyzer) BounceRate(filter *SessionFilter) (float64, error) {
+	filter = analyzer.validateSessionFilter(filter)
+	return analyzer.store.BounceRate(filter)
+}
+
+// AverageSessionDuration returns the average session duration in seconds for
+// given filter. Session duration is max(time)-min(time) per (fingerprint,
+// session), capped at filter.SessionTimeout.
+func (analyzer *Analyzer) AverageSessionDuration(filter *SessionFilter) (int, error) {
+	filter = analyzer.validateSessionFilter(filter)
+	return analyzer.store.AverageSessionDuration(filter)
+}
+
+// PagesPerSession returns the average number of hits per session for given filter.
+func (analyzer *Analyzer) PagesPerSession(filter *SessionFilter) (float64, error) {
+	filter = analyzer.validateSessionFilter(filter)
+	return analyzer.store.PagesPerSession(filter)
+}
+
+// validateSessionFilter applies the usual Filter defaults via validateFilter
+// and fills in SessionTimeout when it wasn't set.
+func (analyzer *Analyzer) validateSessionFilter(filter *SessionFilter) *SessionFilter {
+	if filter == nil {
+		filter = &SessionFilter{}
+	}
+
+	filter.Filter = analyzer.validateFilter(filter.Filter)
+
+	if filter.SessionTimeout <= 0 {
+		filter.SessionTimeout = defaultSessionTimeout
+	}
+
+	return filter
+}
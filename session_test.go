@@ -0,0 +1,90 @@
+package pirsch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnalyzerBounceRate(t *testing.T) {
+	store := NewPostgresStore(db)
+	cleanupDB(t)
+	createSessionTestdata(t, store)
+	analyzer := NewAnalyzer(store)
+	rate, err := analyzer.BounceRate(&SessionFilter{Filter: &Filter{pastDay(0), pastDay(0)}})
+
+	if err != nil {
+		t.Fatalf("BounceRate must be returned, but was: %v", err)
+	}
+
+	if rate != 0.5 {
+		t.Fatalf("Bounce rate not as expected: %v", rate)
+	}
+}
+
+func TestAnalyzerPagesPerSession(t *testing.T) {
+	store := NewPostgresStore(db)
+	cleanupDB(t)
+	createSessionTestdata(t, store)
+	analyzer := NewAnalyzer(store)
+	pages, err := analyzer.PagesPerSession(&SessionFilter{Filter: &Filter{pastDay(0), pastDay(0)}})
+
+	if err != nil {
+		t.Fatalf("PagesPerSession must be returned, but was: %v", err)
+	}
+
+	if pages != 1.5 {
+		t.Fatalf("Pages per session not as expected: %v", pages)
+	}
+}
+
+func TestAnalyzerPagesPerSessionMinSessionDuration(t *testing.T) {
+	store := NewPostgresStore(db)
+	cleanupDB(t)
+	createSessionTestdata(t, store)
+	analyzer := NewAnalyzer(store)
+
+	// fp2's session lasts a minute, fp1 bounces at zero duration, so a
+	// 30-second minimum must exclude fp1 and leave only fp2's two hits.
+	pages, err := analyzer.PagesPerSession(&SessionFilter{
+		Filter:             &Filter{pastDay(0), pastDay(0)},
+		MinSessionDuration: 30 * time.Second,
+	})
+
+	if err != nil {
+		t.Fatalf("PagesPerSession must be returned, but was: %v", err)
+	}
+
+	if pages != 2 {
+		t.Fatalf("Pages per session not as expected: %v", pages)
+	}
+}
+
+// TestStoreRollupSessionStatsPerDay proves the rollup job actually persists a
+// row into session_stats_per_day, rather than just computing the numbers.
+func TestStoreRollupSessionStatsPerDay(t *testing.T) {
+	store := NewPostgresStore(db)
+	cleanupDB(t)
+	createSessionTestdata(t, store)
+
+	if err := store.RollupSessionStatsPerDay(pastDay(0)); err != nil {
+		t.Fatalf("rollup must not return an error, but was: %v", err)
+	}
+
+	var stats SessionStatsPerDay
+
+	if err := store.db.Get(&stats, `SELECT * FROM session_stats_per_day WHERE day = $1`, pastDay(0)); err != nil {
+		t.Fatalf("rolled up row must be persisted, but was: %v", err)
+	}
+
+	if stats.Sessions != 2 || stats.Bounces != 1 {
+		t.Fatalf("session stats not as expected: %+v", stats)
+	}
+}
+
+// createSessionTestdata creates one bounced session (fp1) and one two-hit
+// session (fp2), both on pastDay(0).
+func createSessionTestdata(t *testing.T, store Store) {
+	createHit(t, store, "fp1", "/", "en", "ua1", pastDay(0))
+	createHit(t, store, "fp2", "/", "en", "ua2", pastDay(0))
+	createHit(t, store, "fp2", "/foo", "en", "ua2", pastDay(0).Add(time.Minute))
+}
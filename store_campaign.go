@@ -0,0 +1,109 @@
+package pirsch
+
+import (
+	"database/sql"
+	"time"
+)
+
+// RollupVisitorsPerCampaign computes unique visitors per utm_campaign value for
+// given day and saves them via SaveVisitorsPerCampaign, mirroring the daily
+// VisitorsPerDay rollup. It's meant to be called once per day by a scheduled job.
+func (store *PostgresStore) RollupVisitorsPerCampaign(day time.Time) error {
+	from, to := dayRange(day)
+	stats, err := store.Campaigns(&Filter{From: from, To: to})
+
+	if err != nil {
+		return err
+	}
+
+	for _, stat := range stats {
+		visitors := VisitorsPerCampaign{Day: from, Campaign: stat.Name, Visitors: stat.Visitors}
+
+		if err := store.SaveVisitorsPerCampaign(&visitors); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dayRange returns the start and end timestamps covering day, for use in filters
+// that aggregate a single day's hits for a rollup job.
+func dayRange(day time.Time) (time.Time, time.Time) {
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	return from, from.Add(24*time.Hour - time.Nanosecond)
+}
+
+// SaveVisitorsPerCampaign saves the unique visitor count for a utm_campaign value on a given day.
+func (store *PostgresStore) SaveVisitorsPerCampaign(visitors *VisitorsPerCampaign) error {
+	_, err := store.db.Exec(`INSERT INTO visitors_per_campaign (day, campaign, visitors) VALUES ($1, $2, $3)`,
+		visitors.Day, visitors.Campaign, visitors.Visitors)
+	return err
+}
+
+// CampaignSources returns unique visitors grouped by utm_source for given filter.
+func (store *PostgresStore) CampaignSources(filter *Filter) ([]CampaignStats, error) {
+	return store.campaignStats("utm_source", filter)
+}
+
+// CampaignMediums returns unique visitors grouped by utm_medium for given filter.
+func (store *PostgresStore) CampaignMediums(filter *Filter) ([]CampaignStats, error) {
+	return store.campaignStats("utm_medium", filter)
+}
+
+// Campaigns returns unique visitors grouped by utm_campaign for given filter.
+func (store *PostgresStore) Campaigns(filter *Filter) ([]CampaignStats, error) {
+	return store.campaignStats("utm_campaign", filter)
+}
+
+func (store *PostgresStore) campaignStats(column string, filter *Filter) ([]CampaignStats, error) {
+	var stats []CampaignStats
+	err := store.db.Select(&stats, `SELECT `+column+` name,
+			COUNT(DISTINCT fingerprint) visitors
+		FROM hit
+		WHERE `+column+` IS NOT NULL AND time BETWEEN $1 AND $2
+		GROUP BY `+column+`
+		ORDER BY visitors DESC`, filter.From, filter.To)
+	return stats, err
+}
+
+// ReferrerSources returns unique visitors grouped by ReferrerSource for given filter.
+// The classification happens in Go rather than SQL so ClassifyReferrerSource stays the single
+// source of truth for the hostname -> source mapping. Classification happens before
+// deduping fingerprints, so a visitor who came in through two different referrers
+// classified under the same source is only counted once for that source.
+func (store *PostgresStore) ReferrerSources(filter *Filter) ([]ReferrerSourceStats, error) {
+	var rows []struct {
+		Fingerprint string         `db:"fingerprint"`
+		Referrer    sql.NullString `db:"referrer"`
+	}
+
+	err := store.db.Select(&rows, `SELECT DISTINCT fingerprint, referrer
+		FROM hit
+		WHERE time BETWEEN $1 AND $2`, filter.From, filter.To)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprintsBySource := make(map[ReferrerSource]map[string]struct{})
+
+	for _, row := range rows {
+		// referrer is the full stripped-query URL produced by getReferrer, not a bare hostname
+		source := ClassifyReferrerSource(referrerHostname(row.Referrer.String))
+
+		if fingerprintsBySource[source] == nil {
+			fingerprintsBySource[source] = make(map[string]struct{})
+		}
+
+		fingerprintsBySource[source][row.Fingerprint] = struct{}{}
+	}
+
+	stats := make([]ReferrerSourceStats, 0, len(fingerprintsBySource))
+
+	for source, fingerprints := range fingerprintsBySource {
+		stats = append(stats, ReferrerSourceStats{Source: source, Visitors: len(fingerprints)})
+	}
+
+	return stats, nil
+}
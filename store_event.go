@@ -0,0 +1,57 @@
+package pirsch
+
+// SaveEvent saves a single event.
+func (store *PostgresStore) SaveEvent(event *Event) error {
+	_, err := store.db.Exec(`INSERT INTO event (tenant_id, name, fingerprint, session, path, props, time)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		event.TenantID, event.Name, event.Fingerprint, event.Session, event.Path, event.Props, event.Time)
+	return err
+}
+
+// SaveEvents saves a batch of events in a single transaction, so a failure
+// partway through the batch leaves none of the events committed.
+func (store *PostgresStore) SaveEvents(events []Event) error {
+	tx, err := store.db.Beginx()
+
+	if err != nil {
+		return err
+	}
+
+	for i := range events {
+		event := &events[i]
+
+		if _, err := tx.Exec(`INSERT INTO event (tenant_id, name, fingerprint, session, path, props, time)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			event.TenantID, event.Name, event.Fingerprint, event.Session, event.Path, event.Props, event.Time); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Events returns EventStats (count and unique visitors per event name) for given filter.
+func (store *PostgresStore) Events(filter *Filter) ([]EventStats, error) {
+	var stats []EventStats
+	err := store.db.Select(&stats, `SELECT name,
+			COUNT(1) count,
+			COUNT(DISTINCT fingerprint) visitors
+		FROM event
+		WHERE time BETWEEN $1 AND $2
+		GROUP BY name
+		ORDER BY count DESC`, filter.From, filter.To)
+	return stats, err
+}
+
+// EventBreakdown returns EventBreakdownStats (count per property value) for given event name, property and filter.
+func (store *PostgresStore) EventBreakdown(name, prop string, filter *Filter) ([]EventBreakdownStats, error) {
+	var stats []EventBreakdownStats
+	err := store.db.Select(&stats, `SELECT props->>$1 property_value,
+			COUNT(1) count
+		FROM event
+		WHERE name = $2 AND time BETWEEN $3 AND $4
+		GROUP BY props->>$1
+		ORDER BY count DESC`, prop, name, filter.From, filter.To)
+	return stats, err
+}
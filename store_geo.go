@@ -0,0 +1,45 @@
+package pirsch
+
+import "time"
+
+// RollupVisitorsPerCountry computes unique visitors per country for given day
+// and saves them via SaveVisitorsPerCountry, mirroring the daily VisitorsPerDay
+// rollup. It's meant to be called once per day by a scheduled job.
+func (store *PostgresStore) RollupVisitorsPerCountry(day time.Time) error {
+	from, to := dayRange(day)
+	stats, err := store.VisitorsByCountry(&Filter{From: from, To: to})
+
+	if err != nil {
+		return err
+	}
+
+	for _, stat := range stats {
+		visitors := VisitorsPerCountry{Day: from, Code: stat.Code, Visitors: stat.Visitors}
+
+		if err := store.SaveVisitorsPerCountry(&visitors); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SaveVisitorsPerCountry saves the unique visitor count for a country on a given day.
+func (store *PostgresStore) SaveVisitorsPerCountry(visitors *VisitorsPerCountry) error {
+	_, err := store.db.Exec(`INSERT INTO visitors_per_country (day, code, visitors) VALUES ($1, $2, $3)`,
+		visitors.Day, visitors.Code, visitors.Visitors)
+	return err
+}
+
+// VisitorsByCountry returns unique visitors grouped by country code for given filter.
+// Hits recorded without a GeoDB leave country_code NULL and are excluded.
+func (store *PostgresStore) VisitorsByCountry(filter *Filter) ([]CountryStats, error) {
+	var stats []CountryStats
+	err := store.db.Select(&stats, `SELECT country_code code,
+			COUNT(DISTINCT fingerprint) visitors
+		FROM hit
+		WHERE country_code IS NOT NULL AND time BETWEEN $1 AND $2
+		GROUP BY country_code
+		ORDER BY visitors DESC`, filter.From, filter.To)
+	return stats, err
+}
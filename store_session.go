@@ -0,0 +1,167 @@
+package pirsch
+
+import "time"
+
+// RollupSessionStatsPerDay computes BounceRate/AverageSessionDuration/PagesPerSession
+// for given day and saves the result via SaveSessionStatsPerDay, mirroring the
+// daily VisitorsPerDay rollup. It's meant to be called once per day by a
+// scheduled job.
+func (store *PostgresStore) RollupSessionStatsPerDay(day time.Time) error {
+	from, to := dayRange(day)
+	filter := &SessionFilter{Filter: &Filter{From: from, To: to}, SessionTimeout: defaultSessionTimeout}
+	rows, err := store.sessionAggregates(filter)
+
+	if err != nil {
+		return err
+	}
+
+	sessions := len(rows)
+	bounces := 0
+	totalDuration := 0.0
+	totalHits := 0
+	timeoutSeconds := filter.SessionTimeout.Seconds()
+
+	for _, row := range rows {
+		if row.Hits == 1 {
+			bounces++
+		}
+
+		duration := row.DurationSeconds
+
+		if duration > timeoutSeconds {
+			duration = timeoutSeconds
+		}
+
+		totalDuration += duration
+		totalHits += row.Hits
+	}
+
+	stats := SessionStatsPerDay{Day: from}
+
+	if sessions > 0 {
+		stats.Sessions = sessions
+		stats.Bounces = bounces
+		stats.AvgDurationSeconds = int(totalDuration / float64(sessions))
+		stats.AvgPageviews = float64(totalHits) / float64(sessions)
+	}
+
+	return store.SaveSessionStatsPerDay(&stats)
+}
+
+// SaveSessionStatsPerDay saves a daily session-metrics rollup.
+func (store *PostgresStore) SaveSessionStatsPerDay(stats *SessionStatsPerDay) error {
+	_, err := store.db.Exec(`INSERT INTO session_stats_per_day
+			(day, sessions, bounces, avg_duration_seconds, avg_pageviews)
+		VALUES ($1, $2, $3, $4, $5)`,
+		stats.Day, stats.Sessions, stats.Bounces, stats.AvgDurationSeconds, stats.AvgPageviews)
+	return err
+}
+
+// sessionAggregateQuery groups hits by (fingerprint, session) within the filter's
+// time range, optionally constrained by MinSessionDuration, and is the basis for
+// BounceRate, AverageSessionDuration, and PagesPerSession.
+const sessionAggregateQuery = `
+	SELECT fingerprint,
+		session,
+		COUNT(1) hits,
+		EXTRACT(EPOCH FROM (MAX(time) - MIN(time))) duration_seconds
+	FROM hit
+	WHERE session IS NOT NULL AND time BETWEEN $1 AND $2
+	GROUP BY fingerprint, session`
+
+// sessionAggregateRow is a single (fingerprint, session) group as queried by sessionAggregateQuery.
+type sessionAggregateRow struct {
+	Fingerprint     string    `db:"fingerprint"`
+	Session         time.Time `db:"session"`
+	Hits            int       `db:"hits"`
+	DurationSeconds float64   `db:"duration_seconds"`
+}
+
+// sessionAggregates runs sessionAggregateQuery for given filter, applying a
+// HAVING clause for filter.MinSessionDuration when it's set.
+func (store *PostgresStore) sessionAggregates(filter *SessionFilter) ([]sessionAggregateRow, error) {
+	query := sessionAggregateQuery
+	args := []interface{}{filter.From, filter.To}
+
+	if filter.MinSessionDuration > 0 {
+		query += ` HAVING EXTRACT(EPOCH FROM (MAX(time) - MIN(time))) >= $3`
+		args = append(args, filter.MinSessionDuration.Seconds())
+	}
+
+	var rows []sessionAggregateRow
+	err := store.db.Select(&rows, query, args...)
+	return rows, err
+}
+
+// BounceRate returns the share of sessions consisting of exactly one hit.
+func (store *PostgresStore) BounceRate(filter *SessionFilter) (float64, error) {
+	rows, err := store.sessionAggregates(filter)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	bounces := 0
+
+	for _, row := range rows {
+		if row.Hits == 1 {
+			bounces++
+		}
+	}
+
+	return float64(bounces) / float64(len(rows)), nil
+}
+
+// AverageSessionDuration returns the average session duration in seconds, capped
+// per session at filter.SessionTimeout.
+func (store *PostgresStore) AverageSessionDuration(filter *SessionFilter) (int, error) {
+	rows, err := store.sessionAggregates(filter)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	timeoutSeconds := filter.SessionTimeout.Seconds()
+	total := 0.0
+
+	for _, row := range rows {
+		duration := row.DurationSeconds
+
+		if duration > timeoutSeconds {
+			duration = timeoutSeconds
+		}
+
+		total += duration
+	}
+
+	return int(total / float64(len(rows))), nil
+}
+
+// PagesPerSession returns the average number of hits per session.
+func (store *PostgresStore) PagesPerSession(filter *SessionFilter) (float64, error) {
+	rows, err := store.sessionAggregates(filter)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	total := 0
+
+	for _, row := range rows {
+		total += row.Hits
+	}
+
+	return float64(total) / float64(len(rows)), nil
+}
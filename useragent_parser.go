@@ -0,0 +1,58 @@
+package pirsch
+
+import (
+	useragent "github.com/mssola/user_agent"
+)
+
+// UserAgentParser parses a User-Agent string into structured information.
+// The built-in parser (ParseUserAgent) is used by default; set
+// HitOptions.UserAgentParser to swap it for a different implementation
+// without forking pirsch.
+type UserAgentParser interface {
+	// Parse returns the UserAgentInfo for given User-Agent string.
+	Parse(ua string) UserAgentInfo
+
+	// IsBot returns true if given User-Agent string identifies a bot or crawler.
+	// Consulted by HitFilter.IgnoreHit in addition to BotList.
+	IsBot(ua string) bool
+}
+
+// defaultUserAgentParser wraps the built-in ParseUserAgent. Bot detection is
+// left entirely to HitFilter's BotList, so there's a single, hot-reloadable
+// source of truth for bot User-Agent keywords instead of two lists that can
+// drift apart.
+type defaultUserAgentParser struct{}
+
+// Parse implements the UserAgentParser interface.
+func (defaultUserAgentParser) Parse(ua string) UserAgentInfo {
+	return ParseUserAgent(ua)
+}
+
+// IsBot implements the UserAgentParser interface. It always returns false;
+// use HitFilter.BotList to configure bot User-Agent keywords.
+func (defaultUserAgentParser) IsBot(string) bool {
+	return false
+}
+
+// MssolaUserAgentParser adapts github.com/mssola/user_agent, which handles
+// Edge/IE/Safari quirks better than the built-in parser and can identify bots itself.
+type MssolaUserAgentParser struct{}
+
+// Parse implements the UserAgentParser interface.
+func (MssolaUserAgentParser) Parse(ua string) UserAgentInfo {
+	parsed := useragent.New(ua)
+	browser, version := parsed.Browser()
+	os := parsed.OSInfo()
+
+	return UserAgentInfo{
+		OS:             os.Name,
+		OSVersion:      os.Version,
+		Browser:        browser,
+		BrowserVersion: version,
+	}
+}
+
+// IsBot implements the UserAgentParser interface.
+func (MssolaUserAgentParser) IsBot(ua string) bool {
+	return useragent.New(ua).Bot()
+}